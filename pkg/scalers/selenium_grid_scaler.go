@@ -10,7 +10,11 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	v2beta2 "k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -25,15 +29,48 @@ type seleniumGridScaler struct {
 	metricType v2beta2.MetricTargetType
 	metadata   *seleniumGridScalerMetadata
 	client     *http.Client
+
+	// probeCache coalesces the back-to-back getSessionsCount calls KEDA
+	// makes from IsActive and GetMetrics within the same reconcile, so a
+	// single poll doesn't spin up two throwaway probe sessions. Guarded by
+	// probeMu since GetMetrics and IsActive can be called concurrently.
+	probeMu      sync.Mutex
+	probeCache   *int64
+	probeCacheAt time.Time
 }
 
+const probeCacheTTL = 2 * time.Second
+
 type seleniumGridScalerMetadata struct {
-	url            string
-	browserName    string
-	targetValue    int64
-	browserVersion string
-	unsafeSsl      bool
-	scalerIndex    int
+	url                 string
+	browserName         string
+	targetValue         int64
+	browserVersion      string
+	unsafeSsl           bool
+	scalerIndex         int
+	platformName        string
+	sessionBrowserName  string
+	nodeMaxSessions     int64
+	activationThreshold int64
+	protocol            string
+	probeTimeout        time.Duration
+	sePrefs             map[string]string
+
+	enableBasicAuth  bool
+	username         string
+	password         string
+	enableBearerAuth bool
+	bearerToken      string
+	enableTLS        bool
+	ca               string
+	cert             string
+	key              string
+
+	// nodeMetricsMu guards seenNodeIDs, which recordSeleniumGridMetrics uses
+	// to delete seleniumGridSessionsActive series for nodes that stopped
+	// reporting a matching session so they don't linger forever.
+	nodeMetricsMu sync.Mutex
+	seenNodeIDs   map[string]struct{}
 }
 
 type seleniumResponse struct {
@@ -43,6 +80,7 @@ type seleniumResponse struct {
 type data struct {
 	Grid         grid         `json:"grid"`
 	SessionsInfo sessionsInfo `json:"sessionsInfo"`
+	NodesInfo    nodesInfo    `json:"nodesInfo"`
 }
 
 type grid struct {
@@ -60,17 +98,169 @@ type seleniumSession struct {
 	NodeID       string `json:"nodeId"`
 }
 
+// nodesInfo carries the per-node slot inventory of the grid, used to work
+// out how many slots a single replica can serve on heterogeneous grids.
+type nodesInfo struct {
+	Nodes []seleniumNode `json:"nodes"`
+}
+
+type seleniumNode struct {
+	ID           string `json:"id"`
+	Stereotypes  string `json:"stereotypes"`
+	SessionCount int64  `json:"sessionCount"`
+	MaxSession   int64  `json:"maxSession"`
+}
+
+type nodeStereotype struct {
+	Stereotype capability `json:"stereotype"`
+}
+
 type capability struct {
-	BrowserName    string `json:"browserName"`
-	BrowserVersion string `json:"browserVersion"`
+	BrowserName    string            `json:"browserName"`
+	BrowserVersion string            `json:"browserVersion"`
+	PlatformName   string            `json:"platformName"`
+	VendorPrefs    map[string]string `json:"-"`
+}
+
+// UnmarshalJSON collects well-known fields plus any `se:*` vendor-prefixed
+// capabilities (e.g. `se:vncEnabled`) so the matcher can use them without
+// the struct growing a field per vendor extension.
+func (c *capability) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	if v, ok := raw["browserName"].(string); ok {
+		c.BrowserName = v
+	}
+	if v, ok := raw["browserVersion"].(string); ok {
+		c.BrowserVersion = v
+	}
+	if v, ok := raw["platformName"].(string); ok {
+		c.PlatformName = v
+	}
+
+	c.VendorPrefs = make(map[string]string)
+	for k, v := range raw {
+		if strings.HasPrefix(k, "se:") {
+			// Stereotypes report se:* prefs as arbitrary JSON scalars (e.g.
+			// se:vncEnabled is a bool), so stringify rather than requiring string.
+			if s, ok := v.(string); ok {
+				c.VendorPrefs[k] = s
+			} else {
+				c.VendorPrefs[k] = fmt.Sprintf("%v", v)
+			}
+		}
+	}
+
+	return nil
+}
+
+// capabilityMatcher matches a requested/queued capability against the
+// configured scaler target. It is kept standalone so other grid-backed
+// scalers (Playwright, custom grids) can reuse the same matching rules.
+type capabilityMatcher struct {
+	browserName    string
+	browserVersion string
+	platformName   string
+	sePrefs        map[string]string
+}
+
+func newCapabilityMatcher(meta *seleniumGridScalerMetadata) capabilityMatcher {
+	return capabilityMatcher{
+		browserName:    meta.browserName,
+		browserVersion: meta.browserVersion,
+		platformName:   meta.platformName,
+		sePrefs:        meta.sePrefs,
+	}
+}
+
+// matches reports whether the given capability satisfies the matcher's
+// browserName, browserVersion prefix, (optional) platformName and
+// (optional) `se:*` vendor prefs.
+func (m capabilityMatcher) matches(c capability) bool {
+	if c.BrowserName != m.browserName {
+		return false
+	}
+
+	if m.platformName != "" && c.PlatformName != "" && !strings.EqualFold(c.PlatformName, m.platformName) {
+		return false
+	}
+
+	for k, v := range m.sePrefs {
+		if c.VendorPrefs[k] != v {
+			return false
+		}
+	}
+
+	if c.BrowserVersion == "" {
+		return m.browserVersion == DefaultBrowserVersion
+	}
+
+	return strings.HasPrefix(c.BrowserVersion, m.browserVersion)
+}
+
+// seleniumStatusResponse is the shape of a Selenium 3 hub / Selenoid / Moon
+// `GET /wd/hub/status` response, used as an alternative to the GraphQL API
+// on grids that don't expose it.
+type seleniumStatusResponse struct {
+	Value seleniumStatusValue `json:"value"`
+}
+
+type seleniumStatusValue struct {
+	Ready bool                 `json:"ready"`
+	Nodes []seleniumStatusNode `json:"nodes"`
+}
+
+type seleniumStatusNode struct {
+	ID    string               `json:"id"`
+	Slots []seleniumStatusSlot `json:"slots"`
+}
+
+type seleniumStatusSlot struct {
+	Stereotype capability             `json:"stereotype"`
+	Session    *seleniumStatusSession `json:"session"`
+}
+
+type seleniumStatusSession struct {
+	ID string `json:"sessionId"`
 }
 
 const (
-	DefaultBrowserVersion string = "latest"
+	DefaultBrowserVersion  string = "latest"
+	DefaultNodeMaxSessions int64  = 1
+
+	protocolGraphQL = "graphql"
+	protocolStatus  = "status"
+	protocolProbe   = "probe"
+
+	DefaultProtocol     = protocolGraphQL
+	DefaultProbeTimeout = 5 * time.Second
 )
 
 var seleniumGridLog = logf.Log.WithName("selenium_grid_scaler")
 
+// Selenium grid gauges, registered with the same Prometheus registry the
+// KEDA operator already exposes its own metrics on, so operators can see why
+// a replica count was computed without needing a separate scrape target.
+var (
+	seleniumGridQueueLength = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "keda_selenium_queue_length",
+		Help: "Number of sessions queued for a browser/version/platform on the selenium grid",
+	}, []string{"browser", "version", "platform"})
+
+	seleniumGridSessionsActive = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "keda_selenium_sessions_active",
+		Help: "Number of running sessions for a browser/version on a selenium grid node",
+	}, []string{"browser", "version", "node"})
+
+	seleniumGridMaxSessions = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "keda_selenium_grid_max_sessions",
+		Help: "Grid-wide maximum concurrent sessions reported by the selenium grid",
+	}, []string{"url"})
+)
+
 func NewSeleniumGridScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
@@ -85,6 +275,14 @@ func NewSeleniumGridScaler(config *ScalerConfig) (Scaler, error) {
 
 	httpClient := kedautil.CreateHTTPClient(config.GlobalHTTPTimeout, meta.unsafeSsl)
 
+	if meta.enableTLS {
+		tlsConfig, err := kedautil.NewTLSConfig(meta.cert, meta.key, meta.ca, meta.unsafeSsl)
+		if err != nil {
+			return nil, err
+		}
+		httpClient.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	}
+
 	return &seleniumGridScaler{
 		metricType: metricType,
 		metadata:   meta,
@@ -123,10 +321,112 @@ func parseSeleniumGridScalerMetadata(config *ScalerConfig) (*seleniumGridScalerM
 		meta.unsafeSsl = parsedVal
 	}
 
+	if val, ok := config.TriggerMetadata["platformName"]; ok {
+		meta.platformName = val
+	}
+
+	if val, ok := config.TriggerMetadata["sePrefs"]; ok && val != "" {
+		var sePrefs map[string]string
+		if err := json.Unmarshal([]byte(val), &sePrefs); err != nil {
+			return nil, fmt.Errorf("error parsing sePrefs: %s", err)
+		}
+		meta.sePrefs = sePrefs
+	}
+
+	if val, ok := config.TriggerMetadata["sessionBrowserName"]; ok && val != "" {
+		meta.sessionBrowserName = val
+	} else {
+		meta.sessionBrowserName = meta.browserName
+	}
+
+	if val, ok := config.TriggerMetadata["nodeMaxSessions"]; ok && val != "" {
+		parsedVal, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing nodeMaxSessions: %s", err)
+		}
+		meta.nodeMaxSessions = parsedVal
+	} else {
+		meta.nodeMaxSessions = DefaultNodeMaxSessions
+	}
+
+	if val, ok := config.TriggerMetadata["activationThreshold"]; ok && val != "" {
+		parsedVal, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing activationThreshold: %s", err)
+		}
+		meta.activationThreshold = parsedVal
+	}
+
+	if val, ok := config.TriggerMetadata["protocol"]; ok && val != "" {
+		switch val {
+		case protocolGraphQL, protocolStatus, protocolProbe:
+			meta.protocol = val
+		default:
+			return nil, fmt.Errorf("invalid protocol given in metadata: %s", val)
+		}
+	} else {
+		meta.protocol = DefaultProtocol
+	}
+
+	if val, ok := config.TriggerMetadata["probeTimeout"]; ok && val != "" {
+		parsedVal, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing probeTimeout: %s", err)
+		}
+		if parsedVal <= 0 {
+			return nil, fmt.Errorf("probeTimeout must be greater than 0")
+		}
+		meta.probeTimeout = time.Duration(parsedVal) * time.Second
+	} else {
+		meta.probeTimeout = DefaultProbeTimeout
+	}
+
+	if err := parseSeleniumGridScalerAuthMetadata(config, &meta); err != nil {
+		return nil, err
+	}
+
 	meta.scalerIndex = config.ScalerIndex
 	return &meta, nil
 }
 
+// parseSeleniumGridScalerAuthMetadata reads the TriggerAuthentication-backed
+// credentials for the selenium grid endpoint: basic auth, a bearer token, or
+// mTLS, mirroring the authParams conventions used by other KEDA scalers.
+func parseSeleniumGridScalerAuthMetadata(config *ScalerConfig, meta *seleniumGridScalerMetadata) error {
+	if val, ok := config.AuthParams["username"]; ok && val != "" {
+		meta.username = val
+
+		if val, ok := config.AuthParams["password"]; ok && val != "" {
+			meta.password = val
+		}
+
+		meta.enableBasicAuth = true
+	}
+
+	if val, ok := config.AuthParams["bearerToken"]; ok && val != "" {
+		meta.bearerToken = val
+		meta.enableBearerAuth = true
+	}
+
+	if val, ok := config.AuthParams["ca"]; ok && val != "" {
+		meta.ca = val
+	}
+
+	if val, ok := config.AuthParams["cert"]; ok && val != "" {
+		meta.cert = val
+	}
+
+	if val, ok := config.AuthParams["key"]; ok && val != "" {
+		meta.key = val
+	}
+
+	if meta.ca != "" || meta.cert != "" || meta.key != "" {
+		meta.enableTLS = true
+	}
+
+	return nil
+}
+
 // No cleanup required for selenium grid scaler
 func (s *seleniumGridScaler) Close(context.Context) error {
 	return nil
@@ -167,12 +467,66 @@ func (s *seleniumGridScaler) IsActive(ctx context.Context) (bool, error) {
 		return false, err
 	}
 
-	return v > 0, nil
+	return v > s.metadata.activationThreshold, nil
 }
 
+// getSessionsCount dispatches to the signal source configured via
+// metadata.protocol, so users whose grid doesn't expose the GraphQL API
+// (older Selenium 3 hubs, Selenoid, Moon) can still scale on something.
 func (s *seleniumGridScaler) getSessionsCount(ctx context.Context) (int64, error) {
+	switch s.metadata.protocol {
+	case protocolStatus:
+		return s.getSessionsCountFromStatus(ctx)
+	case protocolProbe:
+		return s.getSessionsCountFromProbeCached(ctx)
+	default:
+		return s.getSessionsCountFromGraphQL(ctx)
+	}
+}
+
+// getSessionsCountFromProbeCached reuses a recent probe result instead of
+// creating a new probe session, since IsActive and GetMetrics both call
+// getSessionsCount once per reconcile.
+func (s *seleniumGridScaler) getSessionsCountFromProbeCached(ctx context.Context) (int64, error) {
+	s.probeMu.Lock()
+	if s.probeCache != nil && time.Since(s.probeCacheAt) < probeCacheTTL {
+		v := *s.probeCache
+		s.probeMu.Unlock()
+		return v, nil
+	}
+	s.probeMu.Unlock()
+
+	v, err := s.getSessionsCountFromProbe(ctx)
+	if err != nil {
+		return -1, err
+	}
+
+	s.probeMu.Lock()
+	s.probeCache = &v
+	s.probeCacheAt = time.Now()
+	s.probeMu.Unlock()
+	return v, nil
+}
+
+// setAuthHeaders applies the TriggerAuthentication-backed basic/bearer
+// credentials to an outgoing request; shared across all three protocols.
+func (s *seleniumGridScaler) setAuthHeaders(req *http.Request) {
+	if s.metadata.enableBasicAuth {
+		req.SetBasicAuth(s.metadata.username, s.metadata.password)
+	}
+
+	if s.metadata.enableBearerAuth {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.metadata.bearerToken))
+	}
+}
+
+func (s *seleniumGridScaler) getSessionsCountFromGraphQL(ctx context.Context) (int64, error) {
 	body, err := json.Marshal(map[string]string{
-		"query": "{ grid { maxSession }, sessionsInfo { sessionQueueRequests, sessions { id, capabilities, nodeId } } }",
+		"query": `{
+			grid { maxSession },
+			sessionsInfo { sessionQueueRequests, sessions { id, capabilities, nodeId } },
+			nodesInfo { nodes { id, stereotypes, sessionCount, maxSession } }
+		}`,
 	})
 
 	if err != nil {
@@ -183,6 +537,8 @@ func (s *seleniumGridScaler) getSessionsCount(ctx context.Context) (int64, error
 	if err != nil {
 		return -1, err
 	}
+	req.Header.Set("Content-Type", "application/json")
+	s.setAuthHeaders(req)
 
 	res, err := s.client.Do(req)
 	if err != nil {
@@ -199,31 +555,208 @@ func (s *seleniumGridScaler) getSessionsCount(ctx context.Context) (int64, error
 	if err != nil {
 		return -1, err
 	}
-	v, err := getCountFromSeleniumResponse(b, s.metadata.browserName, s.metadata.browserVersion)
+	v, err := getCountFromSeleniumResponse(b, s.metadata)
 	if err != nil {
 		return -1, err
 	}
 	return v, nil
 }
 
-func getCountFromSeleniumResponse(b []byte, browserName string, browserVersion string) (int64, error) {
+// getSessionsCountFromStatus counts busy slots matching the configured
+// browser capability from a `GET /wd/hub/status` response, for grids that
+// don't expose the GraphQL API.
+func (s *seleniumGridScaler) getSessionsCountFromStatus(ctx context.Context) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.metadata.url, nil)
+	if err != nil {
+		return -1, err
+	}
+	s.setAuthHeaders(req)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return -1, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		msg := fmt.Sprintf("selenium grid status endpoint returned %d", res.StatusCode)
+		return -1, errors.New(msg)
+	}
+
+	defer res.Body.Close()
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return -1, err
+	}
+
+	return getCountFromStatusResponse(b, s.metadata)
+}
+
+// getSessionsCountFromProbe issues a lightweight `POST /session` for the
+// requested capability and uses the outcome as a saturation signal: a
+// session that can't be created before probeTimeout elapses means the grid
+// has no free matching slot right now. Any other failure, including a non-200
+// response, is returned as an error.
+func (s *seleniumGridScaler) getSessionsCountFromProbe(ctx context.Context) (int64, error) {
+	probeCtx, cancel := context.WithTimeout(ctx, s.metadata.probeTimeout)
+	defer cancel()
+
+	// browserVersion/platformName default to the sentinels "latest" and "",
+	// which the internal matcher reads as "no version requested" and "any
+	// platform"; they aren't real capability values, so only forward them when
+	// the user actually set them, rather than asking a real node for version
+	// "latest".
+	alwaysMatch := map[string]string{
+		"browserName": s.metadata.browserName,
+	}
+	if s.metadata.browserVersion != DefaultBrowserVersion {
+		alwaysMatch["browserVersion"] = s.metadata.browserVersion
+	}
+	if s.metadata.platformName != "" {
+		alwaysMatch["platformName"] = s.metadata.platformName
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"capabilities": map[string]interface{}{
+			"alwaysMatch": alwaysMatch,
+		},
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	req, err := http.NewRequestWithContext(probeCtx, "POST", s.metadata.url, bytes.NewBuffer(body))
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	s.setAuthHeaders(req)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			recordSeleniumGridProbeMetrics(s.metadata, 1)
+			return 1, nil
+		}
+		return -1, err
+	}
+	defer res.Body.Close()
+
+	// A non-200 here is a real error (bad auth, malformed capabilities, grid
+	// outage), not "no matching slot free" -- the grid queues new-session
+	// requests internally and we only learn about saturation by timing out
+	// above, so don't mask misconfiguration as a scale-up signal.
+	if res.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(res.Body)
+		return -1, fmt.Errorf("selenium grid probe endpoint returned %d: %s", res.StatusCode, string(b))
+	}
+
+	var created struct {
+		Value struct {
+			SessionID string `json:"sessionId"`
+		} `json:"value"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&created); err != nil {
+		return -1, err
+	}
+
+	if created.Value.SessionID != "" {
+		s.closeProbeSession(ctx, created.Value.SessionID)
+	}
+
+	recordSeleniumGridProbeMetrics(s.metadata, 0)
+	return 0, nil
+}
+
+// recordSeleniumGridProbeMetrics publishes the probe's saturation signal as
+// the queue-length gauge. Unlike GraphQL/status, a single probe request
+// can't enumerate per-node sessions or grid capacity, so
+// keda_selenium_sessions_active/keda_selenium_grid_max_sessions are not
+// emitted in probe mode.
+func recordSeleniumGridProbeMetrics(meta *seleniumGridScalerMetadata, saturated int64) {
+	platform := meta.platformName
+	if platform == "" {
+		platform = "any"
+	}
+
+	seleniumGridQueueLength.WithLabelValues(meta.browserName, meta.browserVersion, platform).Set(float64(saturated))
+}
+
+// closeProbeSession best-effort deletes the session created by a probe, so
+// probing doesn't itself leak sessions and saturate the grid.
+func (s *seleniumGridScaler) closeProbeSession(ctx context.Context, sessionID string) {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", strings.TrimSuffix(s.metadata.url, "/")+"/"+sessionID, nil)
+	if err != nil {
+		seleniumGridLog.Error(err, "error building request to close probe session")
+		return
+	}
+	s.setAuthHeaders(req)
+
+	if _, err := s.client.Do(req); err != nil {
+		seleniumGridLog.Error(err, "error closing probe session")
+	}
+}
+
+// getCountFromStatusResponse counts busy slots matching the scaler's
+// capabilityMatcher in a Selenium 3 hub / Selenoid / Moon status response,
+// then normalizes by nodeMaxSessions so it reports the same unit (replicas
+// needed) as the GraphQL path rather than a raw session count.
+func getCountFromStatusResponse(b []byte, meta *seleniumGridScalerMetadata) (int64, error) {
+	var status seleniumStatusResponse
+	if err := json.Unmarshal(b, &status); err != nil {
+		return 0, err
+	}
+
+	matcher := newCapabilityMatcher(meta)
+
+	var count int64
+	var totalSlots int64
+	nodeSessionCounts := map[string]int64{}
+	for _, node := range status.Value.Nodes {
+		for _, slot := range node.Slots {
+			totalSlots++
+			if slot.Session != nil && matcher.matches(slot.Stereotype) {
+				count++
+				nodeSessionCounts[node.ID]++
+			}
+		}
+	}
+
+	// The status endpoint has no session-queue concept, so queue depth is
+	// always reported as 0; sessions-active and grid capacity are still
+	// worth publishing so operators don't lose observability just because
+	// they're on protocol=status.
+	recordSeleniumGridMetrics(meta, grid{MaxSession: int(totalSlots)}, 0, nodeSessionCounts)
+
+	if meta.nodeMaxSessions > 0 {
+		count = (count + meta.nodeMaxSessions - 1) / meta.nodeMaxSessions
+	}
+
+	return count, nil
+}
+
+// getCountFromSeleniumResponse counts queued and running sessions that match
+// the scaler's capabilityMatcher and converts that count into a number of
+// replicas, using the per-node slot count (slotsPerReplica) rather than the
+// grid-wide maxSession, so heterogeneous grids scale correctly.
+func getCountFromSeleniumResponse(b []byte, meta *seleniumGridScalerMetadata) (int64, error) {
 	var count int64
+	var queueCount int64
 	var seleniumResponse = seleniumResponse{}
+	nodeSessionCounts := map[string]int64{}
 
 	if err := json.Unmarshal(b, &seleniumResponse); err != nil {
 		return 0, err
 	}
 
+	matcher := newCapabilityMatcher(meta)
+
 	var sessionQueueRequests = seleniumResponse.Data.SessionsInfo.SessionQueueRequests
 	for _, sessionQueueRequest := range sessionQueueRequests {
-		var capability = capability{}
-		if err := json.Unmarshal([]byte(sessionQueueRequest), &capability); err == nil {
-			if capability.BrowserName == browserName {
-				if strings.HasPrefix(capability.BrowserVersion, browserVersion) {
-					count++
-				} else if capability.BrowserVersion == "" && browserVersion == DefaultBrowserVersion {
-					count++
-				}
+		var c = capability{}
+		if err := json.Unmarshal([]byte(sessionQueueRequest), &c); err == nil {
+			if matcher.matches(c) {
+				count++
+				queueCount++
 			}
 		} else {
 			seleniumGridLog.Error(err, fmt.Sprintf("Error when unmarshaling session queue requests: %s", err))
@@ -232,25 +765,91 @@ func getCountFromSeleniumResponse(b []byte, browserName string, browserVersion s
 
 	var sessions = seleniumResponse.Data.SessionsInfo.Sessions
 	for _, session := range sessions {
-		var capability = capability{}
-		if err := json.Unmarshal([]byte(session.Capabilities), &capability); err == nil {
-			if capability.BrowserName == browserName {
-				if strings.HasPrefix(capability.BrowserVersion, browserVersion) {
-					count++
-				} else if browserVersion == DefaultBrowserVersion {
-					count++
-				}
+		var c = capability{}
+		if err := json.Unmarshal([]byte(session.Capabilities), &c); err == nil {
+			if matcher.matches(c) {
+				count++
+				nodeSessionCounts[session.NodeID]++
 			}
 		} else {
 			seleniumGridLog.Error(err, fmt.Sprintf("Error when unmarshaling sessions info: %s", err))
 		}
 	}
 
-	var gridMaxSession = int64(seleniumResponse.Data.Grid.MaxSession)
+	recordSeleniumGridMetrics(meta, seleniumResponse.Data.Grid, queueCount, nodeSessionCounts)
+
+	slotsPerReplica := slotsPerReplicaFromNodes(seleniumResponse.Data.NodesInfo, meta)
 
-	if gridMaxSession > 0 {
-		count = (count + gridMaxSession - 1) / gridMaxSession
+	if slotsPerReplica > 0 {
+		count = (count + slotsPerReplica - 1) / slotsPerReplica
 	}
 
 	return count, nil
 }
+
+// recordSeleniumGridMetrics publishes the queue depth, per-node running
+// session count and grid-wide capacity for this scaler's browser/version so
+// operators can alert on stuck queues independently of the scaling decision.
+// Nodes that reported a matching session on a previous poll but not this one
+// (session finished, node recycled) have their series deleted instead of
+// left at their last value.
+func recordSeleniumGridMetrics(meta *seleniumGridScalerMetadata, g grid, queueCount int64, nodeSessionCounts map[string]int64) {
+	platform := meta.platformName
+	if platform == "" {
+		platform = "any"
+	}
+
+	seleniumGridQueueLength.WithLabelValues(meta.browserName, meta.browserVersion, platform).Set(float64(queueCount))
+
+	meta.nodeMetricsMu.Lock()
+	defer meta.nodeMetricsMu.Unlock()
+
+	for nodeID, sessionCount := range nodeSessionCounts {
+		seleniumGridSessionsActive.WithLabelValues(meta.browserName, meta.browserVersion, nodeID).Set(float64(sessionCount))
+	}
+
+	for nodeID := range meta.seenNodeIDs {
+		if _, ok := nodeSessionCounts[nodeID]; !ok {
+			seleniumGridSessionsActive.DeleteLabelValues(meta.browserName, meta.browserVersion, nodeID)
+		}
+	}
+
+	seenNodeIDs := make(map[string]struct{}, len(nodeSessionCounts))
+	for nodeID := range nodeSessionCounts {
+		seenNodeIDs[nodeID] = struct{}{}
+	}
+	meta.seenNodeIDs = seenNodeIDs
+
+	seleniumGridMaxSessions.WithLabelValues(meta.url).Set(float64(g.MaxSession))
+}
+
+// slotsPerReplicaFromNodes derives how many matching slots a single grid
+// node/replica can serve, from the nodesInfo inventory. It falls back to the
+// configured nodeMaxSessions when no node advertises a matching stereotype,
+// e.g. because the grid hasn't started any matching node yet.
+func slotsPerReplicaFromNodes(ni nodesInfo, meta *seleniumGridScalerMetadata) int64 {
+	matcher := capabilityMatcher{
+		browserName:    meta.sessionBrowserName,
+		browserVersion: meta.browserVersion,
+		platformName:   meta.platformName,
+		sePrefs:        meta.sePrefs,
+	}
+
+	for _, node := range ni.Nodes {
+		var stereotypes []nodeStereotype
+		if err := json.Unmarshal([]byte(node.Stereotypes), &stereotypes); err != nil {
+			seleniumGridLog.Error(err, fmt.Sprintf("Error when unmarshaling node stereotypes: %s", err))
+			continue
+		}
+
+		for _, stereotype := range stereotypes {
+			if matcher.matches(stereotype.Stereotype) {
+				if node.MaxSession > 0 {
+					return node.MaxSession
+				}
+			}
+		}
+	}
+
+	return meta.nodeMaxSessions
+}
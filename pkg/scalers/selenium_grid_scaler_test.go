@@ -0,0 +1,453 @@
+package scalers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapabilityMatcherMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		matcher capabilityMatcher
+		cap     capability
+		want    bool
+	}{
+		{
+			name:    "browser name mismatch",
+			matcher: capabilityMatcher{browserName: "chrome", browserVersion: DefaultBrowserVersion},
+			cap:     capability{BrowserName: "firefox"},
+			want:    false,
+		},
+		{
+			name:    "empty capability version matches default browserVersion",
+			matcher: capabilityMatcher{browserName: "chrome", browserVersion: DefaultBrowserVersion},
+			cap:     capability{BrowserName: "chrome"},
+			want:    true,
+		},
+		{
+			name:    "empty capability version does not match a pinned browserVersion",
+			matcher: capabilityMatcher{browserName: "chrome", browserVersion: "91"},
+			cap:     capability{BrowserName: "chrome"},
+			want:    false,
+		},
+		{
+			name:    "version prefix match",
+			matcher: capabilityMatcher{browserName: "chrome", browserVersion: "91"},
+			cap:     capability{BrowserName: "chrome", BrowserVersion: "91.0.4472"},
+			want:    true,
+		},
+		{
+			name:    "version prefix mismatch",
+			matcher: capabilityMatcher{browserName: "chrome", browserVersion: "91"},
+			cap:     capability{BrowserName: "chrome", BrowserVersion: "92.0.4515"},
+			want:    false,
+		},
+		{
+			name:    "platform mismatch is case-insensitive but still rejects a different platform",
+			matcher: capabilityMatcher{browserName: "chrome", browserVersion: DefaultBrowserVersion, platformName: "LINUX"},
+			cap:     capability{BrowserName: "chrome", PlatformName: "windows"},
+			want:    false,
+		},
+		{
+			name:    "platform match is case-insensitive",
+			matcher: capabilityMatcher{browserName: "chrome", browserVersion: DefaultBrowserVersion, platformName: "LINUX"},
+			cap:     capability{BrowserName: "chrome", PlatformName: "linux"},
+			want:    true,
+		},
+		{
+			name:    "matching se: vendor pref",
+			matcher: capabilityMatcher{browserName: "chrome", browserVersion: DefaultBrowserVersion, sePrefs: map[string]string{"se:vncEnabled": "true"}},
+			cap:     capability{BrowserName: "chrome", VendorPrefs: map[string]string{"se:vncEnabled": "true"}},
+			want:    true,
+		},
+		{
+			name:    "mismatching se: vendor pref",
+			matcher: capabilityMatcher{browserName: "chrome", browserVersion: DefaultBrowserVersion, sePrefs: map[string]string{"se:vncEnabled": "true"}},
+			cap:     capability{BrowserName: "chrome", VendorPrefs: map[string]string{"se:vncEnabled": "false"}},
+			want:    false,
+		},
+		{
+			name:    "missing se: vendor pref",
+			matcher: capabilityMatcher{browserName: "chrome", browserVersion: DefaultBrowserVersion, sePrefs: map[string]string{"se:vncEnabled": "true"}},
+			cap:     capability{BrowserName: "chrome", VendorPrefs: map[string]string{}},
+			want:    false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.matcher.matches(test.cap))
+		})
+	}
+}
+
+func TestSlotsPerReplicaFromNodes(t *testing.T) {
+	meta := &seleniumGridScalerMetadata{
+		browserName:        "chrome",
+		sessionBrowserName: "chrome",
+		browserVersion:     DefaultBrowserVersion,
+		nodeMaxSessions:    3,
+	}
+
+	t.Run("falls back to nodeMaxSessions when no node matches", func(t *testing.T) {
+		ni := nodesInfo{Nodes: []seleniumNode{
+			{Stereotypes: `[{"stereotype":{"browserName":"firefox"}}]`, MaxSession: 1},
+		}}
+		assert.Equal(t, int64(3), slotsPerReplicaFromNodes(ni, meta))
+	})
+
+	t.Run("uses the matching node's maxSession", func(t *testing.T) {
+		ni := nodesInfo{Nodes: []seleniumNode{
+			{Stereotypes: `[{"stereotype":{"browserName":"firefox"}}]`, MaxSession: 1},
+			{Stereotypes: `[{"stereotype":{"browserName":"chrome"}}]`, MaxSession: 5},
+		}}
+		assert.Equal(t, int64(5), slotsPerReplicaFromNodes(ni, meta))
+	})
+
+	t.Run("skips nodes with unparsable stereotypes", func(t *testing.T) {
+		ni := nodesInfo{Nodes: []seleniumNode{
+			{Stereotypes: `not-json`, MaxSession: 5},
+		}}
+		assert.Equal(t, int64(3), slotsPerReplicaFromNodes(ni, meta))
+	})
+}
+
+func TestGetCountFromStatusResponse(t *testing.T) {
+	meta := &seleniumGridScalerMetadata{
+		browserName:     "chrome",
+		browserVersion:  DefaultBrowserVersion,
+		nodeMaxSessions: 2,
+	}
+
+	response := []byte(`{
+		"value": {
+			"ready": true,
+			"nodes": [
+				{
+					"id": "node-1",
+					"slots": [
+						{"stereotype": {"browserName": "chrome"}, "session": {"sessionId": "s1"}},
+						{"stereotype": {"browserName": "chrome"}, "session": {"sessionId": "s2"}},
+						{"stereotype": {"browserName": "firefox"}, "session": {"sessionId": "s3"}}
+					]
+				},
+				{
+					"id": "node-2",
+					"slots": [
+						{"stereotype": {"browserName": "chrome"}, "session": null}
+					]
+				}
+			]
+		}
+	}`)
+
+	count, err := getCountFromStatusResponse(response, meta)
+	assert.NoError(t, err)
+	// 2 busy chrome slots on node-1, nodeMaxSessions=2 -> ceil(2/2) = 1 replica.
+	assert.Equal(t, int64(1), count)
+}
+
+func TestParseSeleniumGridScalerMetadata(t *testing.T) {
+	tests := []struct {
+		name      string
+		metadata  map[string]string
+		wantErr   bool
+		assertion func(t *testing.T, meta *seleniumGridScalerMetadata)
+	}{
+		{
+			name:     "missing url",
+			metadata: map[string]string{"browserName": "chrome"},
+			wantErr:  true,
+		},
+		{
+			name:     "missing browserName",
+			metadata: map[string]string{"url": "http://selenium-hub:4444/graphql"},
+			wantErr:  true,
+		},
+		{
+			name: "defaults are applied",
+			metadata: map[string]string{
+				"url":         "http://selenium-hub:4444/graphql",
+				"browserName": "chrome",
+			},
+			assertion: func(t *testing.T, meta *seleniumGridScalerMetadata) {
+				assert.Equal(t, DefaultBrowserVersion, meta.browserVersion)
+				assert.Equal(t, DefaultNodeMaxSessions, meta.nodeMaxSessions)
+				assert.Equal(t, DefaultProtocol, meta.protocol)
+				assert.Equal(t, DefaultProbeTimeout, meta.probeTimeout)
+				assert.Equal(t, "chrome", meta.sessionBrowserName)
+			},
+		},
+		{
+			name: "invalid protocol",
+			metadata: map[string]string{
+				"url":         "http://selenium-hub:4444/graphql",
+				"browserName": "chrome",
+				"protocol":    "carrier-pigeon",
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid protocol is kept",
+			metadata: map[string]string{
+				"url":         "http://selenium-hub:4444/wd/hub/status",
+				"browserName": "chrome",
+				"protocol":    protocolStatus,
+			},
+			assertion: func(t *testing.T, meta *seleniumGridScalerMetadata) {
+				assert.Equal(t, protocolStatus, meta.protocol)
+			},
+		},
+		{
+			name: "probeTimeout must be positive",
+			metadata: map[string]string{
+				"url":          "http://selenium-hub:4444",
+				"browserName":  "chrome",
+				"protocol":     protocolProbe,
+				"probeTimeout": "0",
+			},
+			wantErr: true,
+		},
+		{
+			name: "probeTimeout is parsed as seconds",
+			metadata: map[string]string{
+				"url":          "http://selenium-hub:4444",
+				"browserName":  "chrome",
+				"protocol":     protocolProbe,
+				"probeTimeout": "10",
+			},
+			assertion: func(t *testing.T, meta *seleniumGridScalerMetadata) {
+				assert.Equal(t, 10*time.Second, meta.probeTimeout)
+			},
+		},
+		{
+			name: "sePrefs is parsed",
+			metadata: map[string]string{
+				"url":         "http://selenium-hub:4444/graphql",
+				"browserName": "chrome",
+				"sePrefs":     `{"se:vncEnabled":"true"}`,
+			},
+			assertion: func(t *testing.T, meta *seleniumGridScalerMetadata) {
+				assert.Equal(t, map[string]string{"se:vncEnabled": "true"}, meta.sePrefs)
+			},
+		},
+		{
+			name: "invalid sePrefs JSON is rejected",
+			metadata: map[string]string{
+				"url":         "http://selenium-hub:4444/graphql",
+				"browserName": "chrome",
+				"sePrefs":     `not-json`,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := &ScalerConfig{TriggerMetadata: test.metadata}
+			meta, err := parseSeleniumGridScalerMetadata(config)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if test.assertion != nil {
+				test.assertion(t, meta)
+			}
+		})
+	}
+}
+
+func TestParseSeleniumGridScalerAuthMetadata(t *testing.T) {
+	t.Run("basic auth", func(t *testing.T) {
+		meta := &seleniumGridScalerMetadata{}
+		config := &ScalerConfig{AuthParams: map[string]string{"username": "user", "password": "pass"}}
+		assert.NoError(t, parseSeleniumGridScalerAuthMetadata(config, meta))
+		assert.True(t, meta.enableBasicAuth)
+		assert.Equal(t, "user", meta.username)
+		assert.Equal(t, "pass", meta.password)
+		assert.False(t, meta.enableBearerAuth)
+		assert.False(t, meta.enableTLS)
+	})
+
+	t.Run("bearer token", func(t *testing.T) {
+		meta := &seleniumGridScalerMetadata{}
+		config := &ScalerConfig{AuthParams: map[string]string{"bearerToken": "token"}}
+		assert.NoError(t, parseSeleniumGridScalerAuthMetadata(config, meta))
+		assert.True(t, meta.enableBearerAuth)
+		assert.Equal(t, "token", meta.bearerToken)
+		assert.False(t, meta.enableBasicAuth)
+	})
+
+	t.Run("mTLS enables TLS when ca/cert/key are set", func(t *testing.T) {
+		meta := &seleniumGridScalerMetadata{}
+		config := &ScalerConfig{AuthParams: map[string]string{"ca": "ca-data", "cert": "cert-data", "key": "key-data"}}
+		assert.NoError(t, parseSeleniumGridScalerAuthMetadata(config, meta))
+		assert.True(t, meta.enableTLS)
+		assert.Equal(t, "ca-data", meta.ca)
+		assert.Equal(t, "cert-data", meta.cert)
+		assert.Equal(t, "key-data", meta.key)
+	})
+
+	t.Run("no auth params configures nothing", func(t *testing.T) {
+		meta := &seleniumGridScalerMetadata{}
+		config := &ScalerConfig{AuthParams: map[string]string{}}
+		assert.NoError(t, parseSeleniumGridScalerAuthMetadata(config, meta))
+		assert.False(t, meta.enableBasicAuth)
+		assert.False(t, meta.enableBearerAuth)
+		assert.False(t, meta.enableTLS)
+	})
+}
+
+func TestGetCountFromSeleniumResponse(t *testing.T) {
+	meta := &seleniumGridScalerMetadata{
+		browserName:        "chrome",
+		sessionBrowserName: "chrome",
+		browserVersion:     DefaultBrowserVersion,
+	}
+
+	response := []byte(`{
+		"data": {
+			"grid": {"maxSession": 10},
+			"sessionsInfo": {
+				"sessionQueueRequests": [
+					"{\"browserName\":\"chrome\"}",
+					"{\"browserName\":\"firefox\"}"
+				],
+				"sessions": [
+					{"id": "s1", "nodeId": "node-1", "capabilities": "{\"browserName\":\"chrome\"}"},
+					{"id": "s2", "nodeId": "node-1", "capabilities": "{\"browserName\":\"chrome\"}"}
+				]
+			},
+			"nodesInfo": {
+				"nodes": [
+					{"id": "node-1", "stereotypes": "[{\"stereotype\":{\"browserName\":\"chrome\"}}]", "maxSession": 3}
+				]
+			}
+		}
+	}`)
+
+	count, err := getCountFromSeleniumResponse(response, meta)
+	assert.NoError(t, err)
+	// 1 queued + 2 running chrome sessions = 3 matches, slotsPerReplica=3 -> ceil(3/3) = 1 replica.
+	assert.Equal(t, int64(1), count)
+}
+
+func TestRecordSeleniumGridMetricsDeletesStaleNodeSeries(t *testing.T) {
+	meta := &seleniumGridScalerMetadata{
+		browserName:    "chrome-stale-test",
+		browserVersion: DefaultBrowserVersion,
+		url:            "http://selenium-hub-stale-test:4444",
+	}
+
+	recordSeleniumGridMetrics(meta, grid{MaxSession: 5}, 0, map[string]int64{"node-1": 2, "node-2": 1})
+	assert.Equal(t, float64(2), testutil.ToFloat64(seleniumGridSessionsActive.WithLabelValues(meta.browserName, meta.browserVersion, "node-1")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(seleniumGridSessionsActive.WithLabelValues(meta.browserName, meta.browserVersion, "node-2")))
+
+	// node-2 no longer reports a matching session; its series must be
+	// deleted rather than left stuck at its last value.
+	recordSeleniumGridMetrics(meta, grid{MaxSession: 5}, 0, map[string]int64{"node-1": 3})
+	assert.Equal(t, float64(3), testutil.ToFloat64(seleniumGridSessionsActive.WithLabelValues(meta.browserName, meta.browserVersion, "node-1")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(seleniumGridSessionsActive.WithLabelValues(meta.browserName, meta.browserVersion, "node-2")))
+}
+
+func TestGetSessionsCountFromProbe(t *testing.T) {
+	t.Run("non-200 is a real error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`bad config`))
+		}))
+		defer server.Close()
+
+		s := &seleniumGridScaler{
+			client:   server.Client(),
+			metadata: &seleniumGridScalerMetadata{url: server.URL, browserName: "chrome", browserVersion: DefaultBrowserVersion, probeTimeout: DefaultProbeTimeout},
+		}
+
+		_, err := s.getSessionsCountFromProbe(context.Background())
+		assert.Error(t, err)
+	})
+
+	t.Run("timeout means saturated", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(50 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		s := &seleniumGridScaler{
+			client:   server.Client(),
+			metadata: &seleniumGridScalerMetadata{url: server.URL, browserName: "chrome", browserVersion: DefaultBrowserVersion, probeTimeout: 1 * time.Millisecond},
+		}
+
+		count, err := s.getSessionsCountFromProbe(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("a created session is closed and counts as not saturated", func(t *testing.T) {
+		var closed bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodDelete {
+				closed = true
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"value":{"sessionId":"probe-session"}}`))
+		}))
+		defer server.Close()
+
+		s := &seleniumGridScaler{
+			client:   server.Client(),
+			metadata: &seleniumGridScalerMetadata{url: server.URL, browserName: "chrome", browserVersion: DefaultBrowserVersion, probeTimeout: DefaultProbeTimeout},
+		}
+
+		count, err := s.getSessionsCountFromProbe(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+		assert.True(t, closed)
+	})
+}
+
+func TestIsActiveRespectsActivationThreshold(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{
+			"data": {
+				"grid": {"maxSession": 1},
+				"sessionsInfo": {
+					"sessionQueueRequests": ["{\"browserName\":\"chrome\"}"],
+					"sessions": []
+				},
+				"nodesInfo": {"nodes": []}
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	s := &seleniumGridScaler{
+		client: server.Client(),
+		metadata: &seleniumGridScalerMetadata{
+			url:            server.URL,
+			browserName:    "chrome",
+			browserVersion: DefaultBrowserVersion,
+			protocol:       protocolGraphQL,
+		},
+	}
+
+	active, err := s.IsActive(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, active, "1 queued session should be active with the zero-value activationThreshold")
+
+	s.metadata.activationThreshold = 5
+	active, err = s.IsActive(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, active, "1 queued session should not be active once activationThreshold is raised above it")
+}